@@ -0,0 +1,165 @@
+// gamestring.go
+
+// A compact single-line encoding for Rullo puzzles, so a board can be passed
+// on the command line, stored in a fixture, or shared in a URL without an
+// input file.
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseBoardString parses the single-line format produced by Board.Encode:
+//
+//	WxH:v11,v12,...,vRC|h1,h2,...,hR|c1,c2,...,cC
+//
+// WxH gives the board's width (columns) and height (rows), in that order,
+// matching the "WxH" of generate's --size flag and the "cols rows" header
+// line of the input-file format read by newBoard.
+//
+// Each value may carry a trailing "+" (already known to be Kept) or "-"
+// (already known to be Removed); with neither, the cell is Unknown.
+func ParseBoardString(s string) (Board, []int, []int, [][]State, error) {
+	header, rest, ok := strings.Cut(s, ":")
+	if !ok {
+		return nil, nil, nil, nil, fmt.Errorf("gamestring: missing ':' in %q", s)
+	}
+	cols, rows, err := parseDims(header)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	parts := strings.Split(rest, "|")
+	if len(parts) != 3 {
+		return nil, nil, nil, nil, fmt.Errorf("gamestring: expected 3 '|'-separated fields, got %v in %q", len(parts), s)
+	}
+	values := strings.Split(parts[0], ",")
+	if len(values) != rows*cols {
+		return nil, nil, nil, nil, fmt.Errorf("gamestring: expected %v cell values, got %v", rows*cols, len(values))
+	}
+	board := make(Board, rows)
+	states := make([][]State, rows)
+	for r := 0; r < rows; r++ {
+		board[r] = make(Row, cols)
+		states[r] = make([]State, cols)
+		for c := 0; c < cols; c++ {
+			value, state, err := parseCell(values[r*cols+c])
+			if err != nil {
+				return nil, nil, nil, nil, err
+			}
+			board[r][c] = value
+			states[r][c] = state
+		}
+	}
+	horz, err := parseInts(parts[1], rows, "horizontal targets")
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	vert, err := parseInts(parts[2], cols, "vertical targets")
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return board, horz, vert, states, nil
+}
+
+// parseDims parses the "WxH" header of a game string into its width
+// (columns) and height (rows), the same order parseSize uses for --size.
+func parseDims(header string) (cols, rows int, err error) {
+	parts := strings.SplitN(header, "x", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("gamestring: invalid dimensions %q, expected WxH", header)
+	}
+	if cols, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, 0, fmt.Errorf("gamestring: invalid column count in %q: %w", header, err)
+	}
+	if rows, err = strconv.Atoi(parts[1]); err != nil {
+		return 0, 0, fmt.Errorf("gamestring: invalid row count in %q: %w", header, err)
+	}
+	return cols, rows, nil
+}
+
+// parseCell parses a single value token, stripping an optional trailing "+"
+// or "-" state marker.
+func parseCell(token string) (value int, state State, err error) {
+	state = Unknown
+	switch {
+	case strings.HasSuffix(token, "+"):
+		state = Kept
+		token = strings.TrimSuffix(token, "+")
+	case strings.HasSuffix(token, "-"):
+		state = Removed
+		token = strings.TrimSuffix(token, "-")
+	}
+	value, err = strconv.Atoi(token)
+	if err != nil {
+		return 0, Unknown, fmt.Errorf("gamestring: invalid cell value %q: %w", token, err)
+	}
+	return value, state, nil
+}
+
+// parseInts parses a comma-separated list of exactly n integers; what names
+// the list in error messages.
+func parseInts(s string, n int, what string) ([]int, error) {
+	tokens := strings.Split(s, ",")
+	if len(tokens) != n {
+		return nil, fmt.Errorf("gamestring: expected %v %v, got %v", n, what, len(tokens))
+	}
+	values := make([]int, n)
+	for i, token := range tokens {
+		v, err := strconv.Atoi(token)
+		if err != nil {
+			return nil, fmt.Errorf("gamestring: invalid %v value %q: %w", what, token, err)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// Encode renders board, horz, vert and states as the single-line game
+// string that ParseBoardString reads back.
+func (board Board) Encode(horz, vert []int, states [][]State) string {
+	var sb strings.Builder
+	rows := len(board)
+	cols := len(board[0])
+	fmt.Fprintf(&sb, "%vx%v:", cols, rows)
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			if r > 0 || c > 0 {
+				sb.WriteString(",")
+			}
+			fmt.Fprintf(&sb, "%v%v", board[r][c], stateSuffix(states, r, c))
+		}
+	}
+	sb.WriteString("|")
+	for i, v := range horz {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		fmt.Fprintf(&sb, "%v", v)
+	}
+	sb.WriteString("|")
+	for i, v := range vert {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		fmt.Fprintf(&sb, "%v", v)
+	}
+	return sb.String()
+}
+
+// stateSuffix returns the "+"/"-" marker for a cell's state, or "" when
+// states is nil or the cell is Unknown.
+func stateSuffix(states [][]State, r, c int) string {
+	if states == nil {
+		return ""
+	}
+	switch states[r][c] {
+	case Kept:
+		return "+"
+	case Removed:
+		return "-"
+	default:
+		return ""
+	}
+}
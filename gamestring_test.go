@@ -0,0 +1,49 @@
+// gamestring_test.go
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseBoardStringRoundTrip(t *testing.T) {
+	board := Board{{1, 2, 3}, {4, 5, 6}}
+	horz := []int{5, 9}
+	vert := []int{4, 5, 6}
+	states := [][]State{{Kept, Removed, Unknown}, {Unknown, Kept, Removed}}
+
+	s := board.Encode(horz, vert, states)
+	gotBoard, gotHorz, gotVert, gotStates, err := ParseBoardString(s)
+	if err != nil {
+		t.Fatalf("ParseBoardString(%q) returned an error: %v", s, err)
+	}
+	if !reflect.DeepEqual(board, gotBoard) {
+		t.Errorf("board = %v, want %v", gotBoard, board)
+	}
+	if !reflect.DeepEqual(horz, gotHorz) {
+		t.Errorf("horz = %v, want %v", gotHorz, horz)
+	}
+	if !reflect.DeepEqual(vert, gotVert) {
+		t.Errorf("vert = %v, want %v", gotVert, vert)
+	}
+	if !reflect.DeepEqual(states, gotStates) {
+		t.Errorf("states = %v, want %v", gotStates, states)
+	}
+}
+
+func TestEncodeDimsMatchColsRows(t *testing.T) {
+	// 3 columns, 2 rows: the header must read "3x2", the same WxH order
+	// generate's --size flag uses, not rows-then-cols.
+	board := Board{{1, 2, 3}, {4, 5, 6}}
+	s := board.Encode([]int{5, 9}, []int{4, 5, 6}, nil)
+	const want = "3x2:"
+	if len(s) < len(want) || s[:len(want)] != want {
+		t.Errorf("Encode header = %q, want prefix %q", s, want)
+	}
+}
+
+func TestParseBoardStringInvalidFieldCount(t *testing.T) {
+	if _, _, _, _, err := ParseBoardString("2x2:1,2,3,4|3,4"); err == nil {
+		t.Fatal("expected an error for a missing '|'-separated field")
+	}
+}
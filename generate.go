@@ -0,0 +1,174 @@
+// generate.go
+
+// A random Rullo puzzle generator with a unique-solution reducer.
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GenerateOptions controls how a puzzle is generated.
+type GenerateOptions struct {
+	Cols, Rows    int
+	Min, Max      int
+	Density       float64 // fraction of cells kept in the intended solution
+	Seed          int64
+	ReduceTimeout time.Duration
+}
+
+// parseSize parses a "WxH" string into its width and height.
+func parseSize(s string) (cols, rows int, err error) {
+	parts := strings.SplitN(s, "x", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid size %q, expected WxH", s)
+	}
+	if cols, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, 0, fmt.Errorf("invalid width in %q: %w", s, err)
+	}
+	if rows, err = strconv.Atoi(parts[1]); err != nil {
+		return 0, 0, fmt.Errorf("invalid height in %q: %w", s, err)
+	}
+	return cols, rows, nil
+}
+
+// maxGenerateAttempts bounds how many random boards GeneratePuzzle will draw
+// before giving up on finding one with a unique solution.
+const maxGenerateAttempts = 100
+
+// GeneratePuzzle builds a board and its row/column targets according to
+// opts, then reduces it: it repeatedly perturbs cell values and keeps each
+// change only if the puzzle still has exactly one solution. If a random
+// board doesn't have a unique solution to begin with, or reduce somehow
+// fails to preserve one, it is discarded and a fresh board is drawn, up to
+// maxGenerateAttempts times or until opts.ReduceTimeout expires. The
+// returned board and targets are guaranteed to have a unique solution; if
+// the budget runs out before one is found, GeneratePuzzle returns an error.
+func GeneratePuzzle(opts GenerateOptions) (Board, []int, []int, error) {
+	if opts.Cols <= 0 || opts.Rows <= 0 {
+		return nil, nil, nil, fmt.Errorf("size must be positive, got %vx%v", opts.Cols, opts.Rows)
+	}
+	if opts.Min > opts.Max {
+		return nil, nil, nil, fmt.Errorf("min (%v) must not exceed max (%v)", opts.Min, opts.Max)
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+
+	ctx := context.Background()
+	if opts.ReduceTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.ReduceTimeout)
+		defer cancel()
+	}
+
+	for attempt := 0; attempt < maxGenerateAttempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			return nil, nil, nil, fmt.Errorf("generate: timed out looking for a unique-solution puzzle")
+		default:
+		}
+		board, horz, vert := randomPuzzle(rng, opts)
+		if !hasUniqueSolution(ctx, board, horz, vert) {
+			continue // this random board is already ambiguous; draw another
+		}
+		reduce(ctx, rng, board, horz, vert, opts)
+		if !hasUniqueSolution(ctx, board, horz, vert) {
+			continue // reduce should preserve uniqueness, but don't ship it if it didn't
+		}
+		return board, horz, vert, nil
+	}
+	return nil, nil, nil, fmt.Errorf("generate: could not find a unique-solution puzzle after %v attempts", maxGenerateAttempts)
+}
+
+// randomPuzzle draws a random board and a random kept-subset according to
+// opts, and returns the board together with the row/column targets implied
+// by that subset.
+func randomPuzzle(rng *rand.Rand, opts GenerateOptions) (Board, []int, []int) {
+	board := make(Board, opts.Rows)
+	kept := make([][]bool, opts.Rows)
+	for r := 0; r < opts.Rows; r++ {
+		board[r] = make(Row, opts.Cols)
+		kept[r] = make([]bool, opts.Cols)
+		for c := 0; c < opts.Cols; c++ {
+			board[r][c] = opts.Min + rng.Intn(opts.Max-opts.Min+1)
+			kept[r][c] = rng.Float64() < opts.Density
+		}
+	}
+	horz, vert := targetsFromKept(board, kept)
+	return board, horz, vert
+}
+
+// targetsFromKept computes the row and column totals implied by which cells
+// are kept.
+func targetsFromKept(board Board, kept [][]bool) (horz, vert []int) {
+	rows, cols := len(board), len(board[0])
+	horz = make([]int, rows)
+	vert = make([]int, cols)
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			if kept[r][c] {
+				horz[r] += board[r][c]
+				vert[c] += board[r][c]
+			}
+		}
+	}
+	return horz, vert
+}
+
+// reduce perturbs random cells of board, keeping each change only if the
+// puzzle (board, horz, vert) still has exactly one solution. It mutates
+// board in place and stops once ctx expires.
+func reduce(ctx context.Context, rng *rand.Rand, board Board, horz, vert []int, opts GenerateOptions) {
+	rows, cols := len(board), len(board[0])
+	attempts := rows * cols * 4
+	for i := 0; i < attempts; i++ {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		r, c := rng.Intn(rows), rng.Intn(cols)
+		old := board[r][c]
+		board[r][c] = opts.Min + rng.Intn(opts.Max-opts.Min+1)
+		if board[r][c] == old || !hasUniqueSolution(ctx, board, horz, vert) {
+			board[r][c] = old
+		}
+	}
+}
+
+// hasUniqueSolution reports whether the given puzzle has exactly one
+// solution.
+func hasUniqueSolution(ctx context.Context, board Board, horz, vert []int) bool {
+	solutions, err := SolveAll(ctx, board, horz, vert)
+	if err != nil {
+		return false
+	}
+	return len(solutions) == 1
+}
+
+// EncodeText renders board, horz and vert in the same multi-line text format
+// newBoard reads, so a generated puzzle round-trips straight back through
+// it.
+func (board Board) EncodeText(horz, vert []int) string {
+	var sb strings.Builder
+	rows := len(board)
+	cols := len(board[0])
+	fmt.Fprintf(&sb, "%v %v\n", cols, rows)
+	for r := 0; r < rows; r++ {
+		for _, v := range board[r] {
+			fmt.Fprintf(&sb, "%v ", v)
+		}
+		fmt.Fprintf(&sb, "%v\n", horz[r])
+	}
+	for i, v := range vert {
+		if i > 0 {
+			sb.WriteString(" ")
+		}
+		fmt.Fprintf(&sb, "%v", v)
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
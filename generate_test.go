@@ -0,0 +1,41 @@
+// generate_test.go
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGeneratePuzzleHasUniqueSolution(t *testing.T) {
+	opts := GenerateOptions{
+		Cols: 4, Rows: 4, Min: 1, Max: 9,
+		Density: 0.5, Seed: 1, ReduceTimeout: 2 * time.Second,
+	}
+	board, horz, vert, err := GeneratePuzzle(opts)
+	if err != nil {
+		t.Fatalf("GeneratePuzzle returned an error: %v", err)
+	}
+	solutions, err := SolveAll(context.Background(), board, horz, vert)
+	if err != nil {
+		t.Fatalf("SolveAll returned an error: %v", err)
+	}
+	if len(solutions) != 1 {
+		t.Fatalf("generated puzzle has %v solutions, want exactly 1", len(solutions))
+	}
+}
+
+func TestGeneratePuzzleRejectsUniformValues(t *testing.T) {
+	// With equal min/max every cell holds the same value, so two kept-subsets
+	// with the same row and column counts cannot be told apart and a random
+	// 6x6 draw is almost never unique; with this seed none within the budget
+	// is, and GeneratePuzzle must report that instead of returning an
+	// ambiguous puzzle.
+	opts := GenerateOptions{
+		Cols: 6, Rows: 6, Min: 3, Max: 3,
+		Density: 0.5, Seed: 1, ReduceTimeout: 2 * time.Second,
+	}
+	if _, _, _, err := GeneratePuzzle(opts); err == nil {
+		t.Fatal("expected GeneratePuzzle to fail for an unsolvable-unique configuration")
+	}
+}
@@ -0,0 +1,158 @@
+// pool.go
+
+// A worker-pool search over row combinations, with column-sum pruning to
+// cut subtrees that can no longer match the column targets.
+package main
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// SolveOptions configures a brute-force search.
+type SolveOptions struct {
+	Jobs    int           // number of workers; 0 means runtime.NumCPU()
+	Timeout time.Duration // 0 means no timeout
+	Limit   int           // stop after this many solutions; 0 means no limit
+}
+
+// suffixMax returns, for every column, the maximum sum still achievable from
+// rows[from:], i.e. the sum of each remaining row's largest plausible value
+// in that column. suffixMax[r] holds the bound for rows[r:]; suffixMax[len(p)]
+// is all zero.
+func (p Plausibles) suffixMax(cols int) [][]int {
+	rows := len(p)
+	suffix := make([][]int, rows+1)
+	suffix[rows] = make([]int, cols)
+	for r := rows - 1; r >= 0; r-- {
+		rowMax := make([]int, cols)
+		for _, row := range p[r] {
+			for col, v := range row {
+				if v > rowMax[col] {
+					rowMax[col] = v
+				}
+			}
+		}
+		suffix[r] = make([]int, cols)
+		for col := range suffix[r] {
+			suffix[r][col] = rowMax[col] + suffix[r+1][col]
+		}
+	}
+	return suffix
+}
+
+// fits reports whether extending partial with row's values still leaves
+// every column reachable against vert, given maxRemaining (the most that
+// the rows after row can still add). It also returns the updated partial sums.
+func fits(partial []int, row Row, vert []int, maxRemaining []int) ([]int, bool) {
+	next := make([]int, len(partial))
+	for col, v := range row {
+		next[col] = partial[col] + v
+		if next[col] > vert[col] || next[col]+maxRemaining[col] < vert[col] {
+			return nil, false
+		}
+	}
+	return next, true
+}
+
+// Assemble walks the remaining rows from rowNo on, pruning any branch whose
+// column sums can no longer reach vert, and sends every fully assembled
+// board to c.
+func (p Plausibles) Assemble(ctx context.Context, c chan Board, board *Board, rowNo int, vert []int, partial []int, maxRemaining [][]int) {
+	select {
+	case <-ctx.Done():
+		return
+	default:
+	}
+	if rowNo >= len(p) { // board is assembled and already known to fit every column
+		c <- (*board).Duplicate() // to avoid data contamination
+		return
+	}
+	for _, row := range p[rowNo] { // choose each plausible solution on this row
+		next, ok := fits(partial, row, vert, maxRemaining[rowNo+1])
+		if !ok {
+			continue // this row can no longer lead to a valid column sum
+		}
+		(*board)[rowNo] = row
+		p.Assemble(ctx, c, board, rowNo+1, vert, next, maxRemaining) // proceed with the following row
+	}
+}
+
+// SolveBruteForce searches every combination of row candidates for boards
+// whose columns also sum to vert, spreading the work over a pool of
+// workers that each take a distinct first row and recurse independently. It
+// stops early once ctx is done or, if opts.Limit is set, once that many
+// solutions have been found.
+func (p Plausibles) SolveBruteForce(ctx context.Context, vert []int, opts SolveOptions) ([]Board, error) {
+	jobs := opts.Jobs
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	if len(p) == 0 {
+		return nil, ctx.Err()
+	}
+	cols := len(vert)
+	maxRemaining := p.suffixMax(cols)
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	work := make(chan Row, len(p[0]))
+	for _, row := range p[0] {
+		work <- row
+	}
+	close(work)
+
+	results := make(chan Board, 100)
+	var wg sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for row := range work {
+				partial, ok := fits(make([]int, cols), row, vert, maxRemaining[1])
+				if !ok {
+					continue
+				}
+				board := make(Board, len(p))
+				board[0] = row
+				p.Assemble(ctx, results, &board, 1, vert, partial, maxRemaining)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var solutions []Board
+	limitReached := false
+	for solution := range results {
+		solved := true
+		for col := range vert {
+			if solution.Sum(col) != vert[col] {
+				solved = false
+				break
+			}
+		}
+		if solved && !limitReached {
+			solutions = append(solutions, solution)
+			if opts.Limit > 0 && len(solutions) >= opts.Limit {
+				limitReached = true
+				cancel() // stop the remaining workers; they'll drain work on their own
+			}
+		}
+	}
+	if limitReached {
+		return solutions, nil
+	}
+	return solutions, parent.Err()
+}
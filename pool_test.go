@@ -0,0 +1,106 @@
+// pool_test.go
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// fixturePuzzle returns a small board with a single known solution: keep
+// {1,2,3}, {5,7}, {9,10,12} and drop {4}, {6,8}, {11}.
+func fixturePuzzle() (Board, []int, []int) {
+	board := Board{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{9, 10, 11, 12},
+	}
+	horz := []int{6, 12, 31}
+	vert := []int{15, 12, 10, 12}
+	return board, horz, vert
+}
+
+func TestSuffixMax(t *testing.T) {
+	board, horz, _ := fixturePuzzle()
+	plausibles := rowPlausibles(board, horz)
+	cols := len(board[0])
+	suffix := plausibles.suffixMax(cols)
+
+	if len(suffix) != len(plausibles)+1 {
+		t.Fatalf("suffixMax returned %v rows, want %v", len(suffix), len(plausibles)+1)
+	}
+	for _, v := range suffix[len(plausibles)] {
+		if v != 0 {
+			t.Fatalf("suffixMax past the last row = %v, want all zero", suffix[len(plausibles)])
+		}
+	}
+	// The bound for the last row alone is the max value any of its
+	// plausible combinations can contribute to each column. Only dropping
+	// the 11 makes that row sum to 31, so its one plausible row is
+	// (9,10,0,12).
+	want := []int{9, 10, 0, 12}
+	if !reflect.DeepEqual(suffix[len(plausibles)-1], want) {
+		t.Errorf("suffixMax[last row] = %v, want %v", suffix[len(plausibles)-1], want)
+	}
+}
+
+func TestFitsAcceptsRowWithinBudget(t *testing.T) {
+	vert := []int{10, 10}
+	maxRemaining := []int{10, 10}
+	next, ok := fits([]int{0, 0}, Row{3, 4}, vert, maxRemaining)
+	if !ok {
+		t.Fatal("expected a row within every column's target to fit")
+	}
+	if !reflect.DeepEqual(next, []int{3, 4}) {
+		t.Errorf("fits partial = %v, want [3 4]", next)
+	}
+}
+
+func TestFitsRejectsRowExceedingTarget(t *testing.T) {
+	vert := []int{10, 10}
+	maxRemaining := []int{0, 0}
+	if _, ok := fits([]int{0, 0}, Row{11, 0}, vert, maxRemaining); ok {
+		t.Error("expected a row that overshoots a column target to be rejected")
+	}
+}
+
+func TestFitsRejectsRowThatCanNoLongerReachTarget(t *testing.T) {
+	vert := []int{20, 20}
+	maxRemaining := []int{5, 5}
+	if _, ok := fits([]int{0, 0}, Row{6, 0}, vert, maxRemaining); ok {
+		t.Error("expected a row that, even with maxRemaining, can't reach a column target to be rejected")
+	}
+}
+
+// TestSolveBruteForceMatchesSolveAll runs the worker-pool brute-force search
+// and the propagation solver over the same puzzle and checks they agree,
+// guarding against the worker pool silently dropping or duplicating a
+// solution. Run with -race to also exercise the pool's concurrency.
+func TestSolveBruteForceMatchesSolveAll(t *testing.T) {
+	board, horz, vert := fixturePuzzle()
+
+	propagated, err := SolveAll(context.Background(), board, horz, vert)
+	if err != nil {
+		t.Fatalf("SolveAll returned an error: %v", err)
+	}
+
+	plausibles := rowPlausibles(board, horz)
+	bruteForced, err := plausibles.SolveBruteForce(context.Background(), vert, SolveOptions{Jobs: 4})
+	if err != nil {
+		t.Fatalf("SolveBruteForce returned an error: %v", err)
+	}
+
+	if len(propagated) != len(bruteForced) {
+		t.Fatalf("got %v propagation solution(s) but %v brute-force solution(s)", len(propagated), len(bruteForced))
+	}
+	want := make(map[string]bool, len(propagated))
+	for _, sol := range propagated {
+		want[fmt.Sprint(sol.Board)] = true
+	}
+	for _, sol := range bruteForced {
+		if !want[fmt.Sprint(sol)] {
+			t.Errorf("brute-force solution %v not found among propagation solutions", sol)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// pdf.go
+
+package render
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// renderPDF writes board, horz, vert and (optionally) sol as a one-page PDF
+// document.
+func renderPDF(w io.Writer, board [][]int, horz, vert []int, sol [][]bool, opts RenderOptions) error {
+	rows, cols := len(board), len(board[0])
+	size := float64(opts.cellSize())
+
+	pdf := gofpdf.New("P", "pt", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "", size/2)
+	pdf.SetLineWidth(1)
+
+	left, top := pdf.GetX(), pdf.GetY()
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			x, y := left+float64(c)*size, top+float64(r)*size
+			pdfCell(pdf, x, y, size, board[r][c], kept(sol, r, c), removed(sol, r, c))
+		}
+		pdfTotal(pdf, left+float64(cols)*size, top+float64(r)*size, size, horz[r])
+	}
+	for c := 0; c < cols; c++ {
+		pdfTotal(pdf, left+float64(c)*size, top+float64(rows)*size, size, vert[c])
+	}
+
+	if err := pdf.Output(w); err != nil {
+		return fmt.Errorf("render: writing PDF: %w", err)
+	}
+	return nil
+}
+
+// pdfCell draws one board cell, filling it grey and striking its value out
+// when removed is true.
+func pdfCell(pdf *gofpdf.Fpdf, x, y, size float64, value int, isKept, isRemoved bool) {
+	switch {
+	case isKept:
+		pdf.SetFillColor(215, 240, 215)
+	case isRemoved:
+		pdf.SetFillColor(217, 217, 217)
+	default:
+		pdf.SetFillColor(255, 255, 255)
+	}
+	pdf.Rect(x, y, size, size, "FD")
+	pdf.SetXY(x, y)
+	pdf.CellFormat(size, size, fmt.Sprintf("%v", value), "", 0, "CM", false, 0, "")
+	if isRemoved {
+		pdf.Line(x+2, y+size-2, x+size-2, y+2)
+	}
+}
+
+// pdfTotal draws a row or column target in bold, in a light-grey cell.
+func pdfTotal(pdf *gofpdf.Fpdf, x, y, size float64, value int) {
+	pdf.SetFillColor(238, 238, 238)
+	pdf.Rect(x, y, size, size, "FD")
+	pdf.SetXY(x, y)
+	pdf.SetFont("Helvetica", "B", size/2)
+	pdf.CellFormat(size, size, fmt.Sprintf("%v", value), "", 0, "CM", false, 0, "")
+	pdf.SetFont("Helvetica", "", size/2)
+}
@@ -0,0 +1,51 @@
+// render.go
+
+// Package render draws a Rullo board, and optionally its solution, to PDF or
+// SVG, so puzzles can be used outside a terminal (print-and-play books,
+// websites, and the like).
+package render
+
+import (
+	"fmt"
+	"io"
+)
+
+// RenderOptions controls how a board is drawn.
+type RenderOptions struct {
+	Format   string // "svg" or "pdf"; "" defaults to "svg"
+	CellSize int    // side of a cell, in points; 0 defaults to 40
+}
+
+// cellSize returns opts.CellSize, or a sensible default when unset.
+func (opts RenderOptions) cellSize() int {
+	if opts.CellSize > 0 {
+		return opts.CellSize
+	}
+	return 40
+}
+
+// Render draws board, with its row targets horz down the right-hand side and
+// column targets vert along the bottom, to w. When sol is not nil, it gives
+// the Kept/Removed state of every cell in the solution: kept cells are
+// highlighted and removed cells are shown crossed out.
+func Render(w io.Writer, board [][]int, horz, vert []int, sol [][]bool, opts RenderOptions) error {
+	switch opts.Format {
+	case "", "svg":
+		return renderSVG(w, board, horz, vert, sol, opts)
+	case "pdf":
+		return renderPDF(w, board, horz, vert, sol, opts)
+	default:
+		return fmt.Errorf("render: unknown format %q, want \"svg\" or \"pdf\"", opts.Format)
+	}
+}
+
+// kept reports whether sol marks the cell at (r, c) as kept. A nil sol means
+// no solution was supplied, so nothing is marked either way.
+func kept(sol [][]bool, r, c int) bool {
+	return sol != nil && sol[r][c]
+}
+
+// removed reports whether sol marks the cell at (r, c) as removed.
+func removed(sol [][]bool, r, c int) bool {
+	return sol != nil && !sol[r][c]
+}
@@ -0,0 +1,76 @@
+// svg.go
+
+package render
+
+import (
+	"fmt"
+	"io"
+)
+
+// renderSVG writes board, horz, vert and (optionally) sol as an SVG
+// document.
+func renderSVG(w io.Writer, board [][]int, horz, vert []int, sol [][]bool, opts RenderOptions) error {
+	rows, cols := len(board), len(board[0])
+	size := opts.cellSize()
+	width, height := (cols+1)*size, (rows+1)*size
+
+	fmt.Fprintf(w, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%v\" height=\"%v\" font-family=\"sans-serif\" font-size=\"%v\">\n",
+		width, height, size/2)
+
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			if err := svgCell(w, c*size, r*size, size, board[r][c], kept(sol, r, c), removed(sol, r, c)); err != nil {
+				return err
+			}
+		}
+		if err := svgTotal(w, cols*size, r*size, size, horz[r]); err != nil {
+			return err
+		}
+	}
+	for c := 0; c < cols; c++ {
+		if err := svgTotal(w, c*size, rows*size, size, vert[c]); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, "</svg>")
+	return err
+}
+
+// svgCell draws one board cell, filling it grey and striking its value out
+// when removed is true.
+func svgCell(w io.Writer, x, y, size, value int, isKept, isRemoved bool) error {
+	fill := "white"
+	if isKept {
+		fill = "#d7f0d7"
+	} else if isRemoved {
+		fill = "#d9d9d9"
+	}
+	if _, err := fmt.Fprintf(w, "  <rect x=\"%v\" y=\"%v\" width=\"%v\" height=\"%v\" fill=\"%v\" stroke=\"black\"/>\n",
+		x, y, size, size, fill); err != nil {
+		return err
+	}
+	cx, cy := x+size/2, y+size/2
+	if _, err := fmt.Fprintf(w, "  <text x=\"%v\" y=\"%v\" text-anchor=\"middle\" dominant-baseline=\"central\">%v</text>\n",
+		cx, cy, value); err != nil {
+		return err
+	}
+	if isRemoved {
+		if _, err := fmt.Fprintf(w, "  <line x1=\"%v\" y1=\"%v\" x2=\"%v\" y2=\"%v\" stroke=\"black\"/>\n",
+			x+2, y+size-2, x+size-2, y+2); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// svgTotal draws a row or column target in bold, in a light-grey cell.
+func svgTotal(w io.Writer, x, y, size, value int) error {
+	if _, err := fmt.Fprintf(w, "  <rect x=\"%v\" y=\"%v\" width=\"%v\" height=\"%v\" fill=\"#eeeeee\" stroke=\"black\"/>\n",
+		x, y, size, size); err != nil {
+		return err
+	}
+	cx, cy := x+size/2, y+size/2
+	_, err := fmt.Fprintf(w, "  <text x=\"%v\" y=\"%v\" text-anchor=\"middle\" dominant-baseline=\"central\" font-weight=\"bold\">%v</text>\n",
+		cx, cy, value)
+	return err
+}
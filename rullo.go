@@ -4,11 +4,16 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"os"
+	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/taflaj/rullo/render"
 	"github.com/taflaj/util/reader"
 )
 
@@ -58,25 +63,6 @@ func (a *Array) Append(solution Row) {
 // Plausibles contains all rows with valid solutions.
 type Plausibles []Array
 
-// Assemble returns a board with a plausible solution.
-func (p Plausibles) Assemble(c chan Board, board *Board, rowNo int) {
-	if rowNo >= len(p) { // board is assembled
-		c <- (*board).Duplicate() // to avoid data contamination
-	} else {
-		for _, row := range p[rowNo] { // choose each plausible solution on this row
-			(*board)[rowNo] = row
-			p.Assemble(c, board, rowNo+1) // proceed with the following row
-		}
-	}
-}
-
-// Iterate is a goroutine that returns all combinations of plausible solutions.
-func (p Plausibles) Iterate(c chan Board) {
-	board := make(Board, len(p))
-	p.Assemble(c, &board, 0)
-	close(c)
-}
-
 // Converts a string to its numeric value.
 func convert(line int, value string) int {
 	n, err := strconv.Atoi(value)
@@ -160,12 +146,12 @@ func newBoard(file string) (Board, []int, []int) {
 	return board, horz, vert
 }
 
-// Explores all possible solutions using brute force.
-func explore(board Board, horz []int, vert []int) {
+// rowPlausibles finds, for each row, every combination of its cells that
+// sums to that row's target.
+func rowPlausibles(board Board, horz []int) Plausibles {
 	rows := len(board)
 	cols := len(board[0])
 	plausibles := make(Plausibles, rows)
-	// find all plausible solutions for each row
 	for r := 0; r < rows; r++ {
 		plausibles[r] = Array{}
 		for i := 0; i < 1<<uint(cols); i++ { // exercise all combinations
@@ -182,29 +168,47 @@ func explore(board Board, horz []int, vert []int) {
 			}
 		}
 	}
-	// match all plausible solutions with one another.
-	n := 0
-	solutions := make(chan Board, 100)
-	go plausibles.Iterate(solutions)
-	for solution := range solutions {
-		// see if the newly assembled board is an actual solution
-		solved := true
-		for col := range solution[0] {
-			if solution.Sum(col) != vert[col] {
-				solved = false // this column failed validation
-				break
-			}
-		}
-		if solved {
-			n++
-			fmt.Printf("%3v: %v\n", n, solution)
-		}
+	return plausibles
+}
+
+// Explores all possible solutions using brute force, spread across a pool of
+// workers.
+func explore(ctx context.Context, board Board, horz []int, vert []int, opts SolveOptions) {
+	plausibles := rowPlausibles(board, horz)
+	solutions, err := plausibles.SolveBruteForce(ctx, vert, opts)
+	for n, solution := range solutions {
+		fmt.Printf("%3v: %v\n", n+1, solution)
+	}
+	if err != nil {
+		fmt.Printf("%v (%v solution(s) found before aborting)\n", err, len(solutions))
 	}
 }
 
-func main() {
-	if len(os.Args) < 2 {
-		fmt.Printf("Usage: %v <input file>\n\n", os.Args[0])
+// solveCmd runs the constraint-propagation solver against an input file.
+func solveCmd(args []string) {
+	fs := flag.NewFlagSet("solve", flag.ExitOnError)
+	timeout := fs.Duration("timeout", 0, "abort the search after this long (0 = no limit)")
+	bruteForce := fs.Bool("brute-force", false, "use the original brute-force search instead")
+	jobs := fs.Int("jobs", runtime.NumCPU(), "number of workers for --brute-force")
+	limit := fs.Int("limit", 0, "stop --brute-force after this many solutions (0 = no limit)")
+	game := fs.String("game", "", "puzzle as a single-line game string (see ParseBoardString), instead of an input file")
+	fs.Parse(args)
+	var board Board
+	var horz, vert []int
+	var states [][]State
+	switch {
+	case *game != "":
+		var err error
+		board, horz, vert, states, err = ParseBoardString(*game)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	case fs.NArg() >= 1:
+		board, horz, vert = newBoard(fs.Arg(0))
+	default:
+		fmt.Printf("Usage: %v solve [--timeout DURATION] <input file>\n", os.Args[0])
+		fmt.Printf("       %v solve --game <game string>\n\n", os.Args[0])
 		text := "You may use your favorite text editor to create the input file.\n" +
 			"Include two numbers on the first line, specifying the width and height of the grid, not including the totals.\n" +
 			"On each line, include the numbers for each row, separated by spaces. The last number should be the total of the row.\n" +
@@ -213,9 +217,145 @@ func main() {
 			"For example:\n" +
 			"4 3\n1 2 3 4 6\n5 6 7 8 12\n9 10 11 12 31\n15 12 10 12"
 		fmt.Println(text)
-	} else {
-		board, horz, vert := newBoard(os.Args[1])
-		fmt.Printf("board = %v\n horz = %v\n vert = %v\n", board, horz, vert)
-		explore(board, horz, vert)
+		return
+	}
+	fmt.Printf("board = %v\n horz = %v\n vert = %v\n", board, horz, vert)
+	ctx := context.Background()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	if *bruteForce {
+		explore(ctx, board, horz, vert, SolveOptions{Jobs: *jobs, Limit: *limit})
+		return
+	}
+	solutions, err := SolveAllFromStates(ctx, board, horz, vert, states)
+	for n, solution := range solutions {
+		fmt.Printf("%3v: %v\n", n+1, solution.Board)
+		fmt.Printf("     %v\n", board.Encode(horz, vert, solution.States))
+	}
+	if err != nil {
+		fmt.Printf("%v (%v solution(s) found before aborting)\n", err, len(solutions))
+	}
+}
+
+// generateCmd builds a random puzzle with a unique solution and prints it in
+// the text input format.
+func generateCmd(args []string) {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	size := fs.String("size", "5x5", "board size as WxH")
+	min := fs.Int("min", 1, "minimum cell value")
+	max := fs.Int("max", 9, "maximum cell value")
+	density := fs.Float64("density", 0.5, "fraction of cells kept in the solution")
+	seed := fs.Int64("seed", time.Now().UnixNano(), "random seed")
+	reduceTimeout := fs.Duration("reduce-timeout", 5*time.Second, "time budget for the reduction pass")
+	fs.Parse(args)
+	cols, rows, err := parseSize(*size)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	board, horz, vert, err := GeneratePuzzle(GenerateOptions{
+		Cols: cols, Rows: rows, Min: *min, Max: *max,
+		Density: *density, Seed: *seed, ReduceTimeout: *reduceTimeout,
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Print(board.EncodeText(horz, vert))
+}
+
+// toGrid converts a Board to the plain [][]int the render package works
+// with, since package main cannot export Board for it to import.
+func toGrid(board Board) [][]int {
+	grid := make([][]int, len(board))
+	for r, row := range board {
+		grid[r] = []int(row)
+	}
+	return grid
+}
+
+// toKept converts a solution's cell states to the plain [][]bool the render
+// package works with, since package main cannot export State for it to
+// import. Unlike inferring Kept/Removed from a solved cell's value, this
+// reports the solver's actual state even for a legitimately zero-valued
+// kept cell.
+func toKept(states [][]State) [][]bool {
+	grid := make([][]bool, len(states))
+	for r, row := range states {
+		grid[r] = make([]bool, len(row))
+		for c, state := range row {
+			grid[r][c] = state == Kept
+		}
+	}
+	return grid
+}
+
+// renderCmd draws a board, and its first solution, to PDF or SVG.
+func renderCmd(args []string) {
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	format := fs.String("format", "svg", "output format: pdf or svg")
+	out := fs.String("out", "", "output file (default: standard output)")
+	cellSize := fs.Int("cell-size", 0, "cell side, in points (default 40)")
+	game := fs.String("game", "", "puzzle as a single-line game string, instead of an input file")
+	fs.Parse(args)
+	var board Board
+	var horz, vert []int
+	var states [][]State
+	switch {
+	case *game != "":
+		var err error
+		board, horz, vert, states, err = ParseBoardString(*game)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	case fs.NArg() >= 1:
+		board, horz, vert = newBoard(fs.Arg(0))
+	default:
+		fmt.Printf("Usage: %v render [--format pdf|svg] [--out file] [--cell-size N] <input file>\n", os.Args[0])
+		fmt.Printf("       %v render [--format pdf|svg] [--out file] [--cell-size N] --game <game string>\n", os.Args[0])
+		return
+	}
+	solutions, err := SolveAllFromStates(context.Background(), board, horz, vert, states)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	var sol [][]bool
+	if len(solutions) > 0 {
+		sol = toKept(solutions[0].States)
+	}
+	w := os.Stdout
+	if *out != "" {
+		w, err = os.Create(*out)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		defer w.Close()
+	}
+	opts := render.RenderOptions{Format: *format, CellSize: *cellSize}
+	if err := render.Render(w, toGrid(board), horz, vert, sol, opts); err != nil {
+		fmt.Println(err)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		solveCmd(nil)
+		return
+	}
+	switch os.Args[1] {
+	case "solve":
+		solveCmd(os.Args[2:])
+	case "generate":
+		generateCmd(os.Args[2:])
+	case "render":
+		renderCmd(os.Args[2:])
+	default:
+		solveCmd(os.Args[1:]) // backward-compatible: first arg is the input file
 	}
 }
@@ -0,0 +1,58 @@
+// rullo_test.go
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// renderGameToFile runs renderCmd against game and returns the contents of
+// the file it wrote.
+func renderGameToFile(t *testing.T, game string) string {
+	t.Helper()
+	out := filepath.Join(t.TempDir(), "board.svg")
+	renderCmd([]string{"--format", "svg", "--out", out, "--game", game})
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("reading rendered output: %v", err)
+	}
+	return string(data)
+}
+
+// firstCell returns the SVG markup for the first board cell, i.e. everything
+// up to (but not including) the second <rect>.
+func firstCell(svg string) string {
+	first := strings.Index(svg, "<rect")
+	if first < 0 {
+		return ""
+	}
+	second := strings.Index(svg[first+1:], "<rect")
+	if second < 0 {
+		return svg[first:]
+	}
+	return svg[first : first+1+second]
+}
+
+func TestRenderCmdRespectsGameStateMarkers(t *testing.T) {
+	// Both game strings describe the same otherwise-ambiguous puzzle (cell
+	// (0,0) could be either Kept or Removed); only the marker on that cell
+	// differs, and renderCmd must render each one as pinned, not silently
+	// resolve them the same way.
+	kept := renderGameToFile(t, "2x2:1+,1-,1-,1+|1,1|1,1")
+	removed := renderGameToFile(t, "2x2:1-,1+,1+,1-|1,1|1,1")
+
+	keptCell := firstCell(kept)
+	removedCell := firstCell(removed)
+
+	if !strings.Contains(keptCell, "#d7f0d7") {
+		t.Errorf("cell (0,0) pinned Kept was not rendered as kept:\n%v", keptCell)
+	}
+	if strings.Contains(keptCell, "<line") {
+		t.Errorf("cell (0,0) pinned Kept was rendered crossed out:\n%v", keptCell)
+	}
+	if !strings.Contains(removedCell, "#d9d9d9") || !strings.Contains(removedCell, "<line") {
+		t.Errorf("cell (0,0) pinned Removed was not rendered as removed:\n%v", removedCell)
+	}
+}
@@ -0,0 +1,370 @@
+// solver.go
+
+// A constraint-propagation solver for Rullo boards, with recursive backtracking
+// for whatever cells propagation alone cannot resolve.
+package main
+
+import (
+	"context"
+	"errors"
+)
+
+// State represents what is known about a cell's participation in the solution.
+type State int
+
+// The possible states of a cell.
+const (
+	Unknown State = iota
+	Kept
+	Removed
+)
+
+// Cell pairs a board value with what is currently known about it.
+type Cell struct {
+	Value int
+	State State
+}
+
+// ErrTimeout is returned by SolveAll when the context expires before the
+// search completes.
+var ErrTimeout = errors.New("solver: timed out")
+
+// Solution pairs a solved board with the Kept/Removed state of every cell
+// that produced it, so callers don't have to re-infer the state from
+// whether a cell's value happens to be zero (which is ambiguous: a
+// legitimately zero-valued cell looks the same as a Removed one).
+type Solution struct {
+	Board  Board
+	States [][]State
+}
+
+// Solver owns a working copy of the board together with the row/column
+// targets and the propagation state of every cell.
+type Solver struct {
+	rows, cols int
+	cells      [][]Cell
+	horz, vert []int
+}
+
+// NewSolver creates a Solver from a board and its row/column targets. Every
+// cell starts out Unknown.
+func NewSolver(board Board, horz, vert []int) *Solver {
+	return NewSolverFromStates(board, horz, vert, nil)
+}
+
+// NewSolverFromStates is like NewSolver, but seeds each cell with an
+// already-known state instead of Unknown. states may be nil, in which case
+// it behaves exactly like NewSolver.
+func NewSolverFromStates(board Board, horz, vert []int, states [][]State) *Solver {
+	rows := len(board)
+	cols := len(board[0])
+	cells := make([][]Cell, rows)
+	for r := 0; r < rows; r++ {
+		cells[r] = make([]Cell, cols)
+		for c := 0; c < cols; c++ {
+			state := Unknown
+			if states != nil {
+				state = states[r][c]
+			}
+			cells[r][c] = Cell{Value: board[r][c], State: state}
+		}
+	}
+	return &Solver{rows: rows, cols: cols, cells: cells, horz: horz, vert: vert}
+}
+
+// duplicate returns an independent copy of the solver, used when branching.
+func (s *Solver) duplicate() *Solver {
+	cells := make([][]Cell, s.rows)
+	for r := range s.cells {
+		cells[r] = make([]Cell, s.cols)
+		copy(cells[r], s.cells[r])
+	}
+	return &Solver{rows: s.rows, cols: s.cols, cells: cells, horz: s.horz, vert: s.vert}
+}
+
+// board reassembles a Board from the Kept cells (Removed and Unknown cells
+// contribute a zero value).
+func (s *Solver) board() Board {
+	board := make(Board, s.rows)
+	for r := 0; r < s.rows; r++ {
+		board[r] = make(Row, s.cols)
+		for c := 0; c < s.cols; c++ {
+			if s.cells[r][c].State == Kept {
+				board[r][c] = s.cells[r][c].Value
+			}
+		}
+	}
+	return board
+}
+
+// states returns a copy of every cell's current State, independent of the
+// Solver so callers may keep it past further solving.
+func (s *Solver) states() [][]State {
+	states := make([][]State, s.rows)
+	for r := 0; r < s.rows; r++ {
+		states[r] = make([]State, s.cols)
+		for c := 0; c < s.cols; c++ {
+			states[r][c] = s.cells[r][c].State
+		}
+	}
+	return states
+}
+
+// bounds returns the minimum and maximum sum a line (row or column) can still
+// reach, given what is currently Kept and Unknown along it.
+func bounds(line []Cell) (min, max int) {
+	for _, cell := range line {
+		switch cell.State {
+		case Kept:
+			min += cell.Value
+			max += cell.Value
+		case Unknown:
+			max += cell.Value
+		}
+	}
+	return
+}
+
+// feasible reports whether some subset of the Unknown cells in line can sum,
+// together with what is already Kept, to exactly target.
+func feasible(line []Cell, target int) bool {
+	kept := 0
+	var unknowns []int
+	for _, cell := range line {
+		switch cell.State {
+		case Kept:
+			kept += cell.Value
+		case Unknown:
+			unknowns = append(unknowns, cell.Value)
+		}
+	}
+	return subsetSum(unknowns, target-kept)
+}
+
+// subsetSum reports whether some subset of values sums exactly to target. It
+// fills a reachability table of size target+1 in O(len(values)*target) time,
+// rather than recursing over every one of the 2^len(values) subsets, which is
+// what made propagateLine intractable on anything past a toy board.
+func subsetSum(values []int, target int) bool {
+	if target < 0 {
+		return false
+	}
+	reachable := make([]bool, target+1)
+	reachable[0] = true
+	for _, v := range values {
+		if v <= 0 {
+			continue
+		}
+		for s := target; s >= v; s-- {
+			if reachable[s-v] {
+				reachable[s] = true
+			}
+		}
+	}
+	return reachable[target]
+}
+
+// row returns the cells of row r.
+func (s *Solver) row(r int) []Cell {
+	return s.cells[r]
+}
+
+// column returns the cells of column c.
+func (s *Solver) column(c int) []Cell {
+	col := make([]Cell, s.rows)
+	for r := 0; r < s.rows; r++ {
+		col[r] = s.cells[r][c]
+	}
+	return col
+}
+
+// setColumn writes the states of col back into column c.
+func (s *Solver) setColumn(c int, col []Cell) {
+	for r := 0; r < s.rows; r++ {
+		s.cells[r][c].State = col[r].State
+	}
+}
+
+// contradiction reports whether any line can no longer possibly reach its
+// target.
+func (s *Solver) contradiction() bool {
+	for r := 0; r < s.rows; r++ {
+		min, max := bounds(s.row(r))
+		if s.horz[r] < min || s.horz[r] > max {
+			return true
+		}
+	}
+	for c := 0; c < s.cols; c++ {
+		min, max := bounds(s.column(c))
+		if s.vert[c] < min || s.vert[c] > max {
+			return true
+		}
+	}
+	return false
+}
+
+// solved reports whether every cell has been resolved.
+func (s *Solver) solved() bool {
+	for r := 0; r < s.rows; r++ {
+		for c := 0; c < s.cols; c++ {
+			if s.cells[r][c].State == Unknown {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// propagateLine resolves the Unknown cells of line against target, either
+// directly (target equals the min or max reachable sum) or by eliminating
+// individual assignments that would make the target unreachable. It returns
+// whether anything changed.
+func propagateLine(line []Cell, target int) bool {
+	min, max := bounds(line)
+	changed := false
+	switch target {
+	case min:
+		for i := range line {
+			if line[i].State == Unknown {
+				line[i].State = Removed
+				changed = true
+			}
+		}
+		return changed
+	case max:
+		for i := range line {
+			if line[i].State == Unknown {
+				line[i].State = Kept
+				changed = true
+			}
+		}
+		return changed
+	}
+	for i := range line {
+		if line[i].State != Unknown {
+			continue
+		}
+		trial := make([]Cell, len(line))
+		copy(trial, line)
+		trial[i].State = Kept
+		if !feasible(trial, target) {
+			line[i].State = Removed
+			changed = true
+			continue
+		}
+		trial[i].State = Removed
+		if !feasible(trial, target) {
+			line[i].State = Kept
+			changed = true
+		}
+	}
+	return changed
+}
+
+// propagate runs row and column propagation until a fixed point is reached.
+func (s *Solver) propagate() {
+	for {
+		changed := false
+		for r := 0; r < s.rows; r++ {
+			if propagateLine(s.row(r), s.horz[r]) {
+				changed = true
+			}
+		}
+		for c := 0; c < s.cols; c++ {
+			col := s.column(c)
+			if propagateLine(col, s.vert[c]) {
+				s.setColumn(c, col)
+				changed = true
+			}
+		}
+		if !changed {
+			return
+		}
+	}
+}
+
+// branchCell picks the Unknown cell with the fewest feasible assignments
+// (Kept, Removed, or both), which keeps the search tree as narrow as
+// possible. It returns ok=false if every cell is already resolved.
+func (s *Solver) branchCell() (row, col, options int, ok bool) {
+	best := 3
+	for r := 0; r < s.rows && best > 1; r++ {
+		for c := 0; c < s.cols; c++ {
+			if s.cells[r][c].State != Unknown {
+				continue
+			}
+			n := s.options(r, c)
+			if n < best {
+				row, col, options, ok = r, c, n, true
+				best = n
+				if best <= 1 {
+					break
+				}
+			}
+		}
+	}
+	return
+}
+
+// options reports how many of {Kept, Removed} remain locally consistent for
+// the cell at (r, c).
+func (s *Solver) options(r, c int) int {
+	n := 0
+	for _, state := range [2]State{Kept, Removed} {
+		s.cells[r][c].State = state
+		if !s.contradiction() {
+			n++
+		}
+	}
+	s.cells[r][c].State = Unknown
+	return n
+}
+
+// TrySolveRecurse propagates as far as possible, then branches on the
+// remaining Unknown cells, collecting every completed solution into
+// solutions. It aborts as soon as ctx is done.
+func (s *Solver) TrySolveRecurse(ctx context.Context, solutions *[]Solution) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	s.propagate()
+	if s.contradiction() {
+		return nil
+	}
+	if s.solved() {
+		*solutions = append(*solutions, Solution{Board: s.board(), States: s.states()})
+		return nil
+	}
+	r, c, _, ok := s.branchCell()
+	if !ok { // nothing left to branch on, yet not solved: shouldn't happen
+		return nil
+	}
+	for _, state := range [2]State{Kept, Removed} {
+		branch := s.duplicate()
+		branch.cells[r][c].State = state
+		if err := branch.TrySolveRecurse(ctx, solutions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SolveAll is the public entry point: it returns every solution for the
+// board, or ErrTimeout if ctx expires first.
+func SolveAll(ctx context.Context, board Board, horz, vert []int) ([]Solution, error) {
+	return SolveAllFromStates(ctx, board, horz, vert, nil)
+}
+
+// SolveAllFromStates is like SolveAll, but starts from cells that are
+// already partly resolved (see NewSolverFromStates).
+func SolveAllFromStates(ctx context.Context, board Board, horz, vert []int, states [][]State) ([]Solution, error) {
+	solver := NewSolverFromStates(board, horz, vert, states)
+	var solutions []Solution
+	err := solver.TrySolveRecurse(ctx, &solutions)
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return solutions, ErrTimeout
+	}
+	return solutions, err
+}
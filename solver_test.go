@@ -0,0 +1,65 @@
+// solver_test.go
+package main
+
+import "testing"
+
+func TestSubsetSum(t *testing.T) {
+	cases := []struct {
+		name   string
+		values []int
+		target int
+		want   bool
+	}{
+		{"empty values, zero target", nil, 0, true},
+		{"empty values, nonzero target", nil, 5, false},
+		{"negative target", []int{1, 2, 3}, -1, false},
+		{"exact single value", []int{4}, 4, true},
+		{"exact subset", []int{1, 2, 5, 9}, 7, true},
+		{"no matching subset", []int{2, 4, 6}, 5, false},
+		{"whole set", []int{1, 2, 3}, 6, true},
+		{"zero target with values", []int{1, 2, 3}, 0, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := subsetSum(tc.values, tc.target); got != tc.want {
+				t.Errorf("subsetSum(%v, %v) = %v, want %v", tc.values, tc.target, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPropagateLineDirect(t *testing.T) {
+	line := []Cell{{Value: 3, State: Unknown}, {Value: 5, State: Unknown}, {Value: 2, State: Unknown}}
+	if !propagateLine(line, 0) {
+		t.Fatal("expected target equal to min to trigger a change")
+	}
+	for i, cell := range line {
+		if cell.State != Removed {
+			t.Errorf("cell %v = %v, want Removed", i, cell.State)
+		}
+	}
+}
+
+func TestPropagateLineEliminatesAssignments(t *testing.T) {
+	// target 4 is only reachable by keeping the 4 alone (1+2=3, 1+4=5, 2+4=6,
+	// 1+2+4=7 all miss), so every cell can be resolved in one pass.
+	line := []Cell{{Value: 1, State: Unknown}, {Value: 2, State: Unknown}, {Value: 4, State: Unknown}}
+	if !propagateLine(line, 4) {
+		t.Fatal("expected propagation to make progress")
+	}
+	want := []State{Removed, Removed, Kept}
+	for i, cell := range line {
+		if cell.State != want[i] {
+			t.Errorf("cell %v = %v, want %v", i, cell.State, want[i])
+		}
+	}
+}
+
+func TestPropagateLineNoProgress(t *testing.T) {
+	// target 5 is reachable either by {3,2} or {5}, so no cell can be
+	// resolved yet.
+	line := []Cell{{Value: 3, State: Unknown}, {Value: 5, State: Unknown}, {Value: 2, State: Unknown}}
+	if propagateLine(line, 5) {
+		t.Fatal("did not expect propagation to make progress when both assignments remain feasible")
+	}
+}